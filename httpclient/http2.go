@@ -0,0 +1,111 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// HTTP2Option configures the *http2.Transport installed by WithHTTP2 or
+// WithForceHTTP2.
+type HTTP2Option func(*http2.Transport)
+
+// WithReadIdleTimeout sets how often a health-check PING is sent on an
+// otherwise idle HTTP/2 connection.
+func WithReadIdleTimeout(d time.Duration) HTTP2Option {
+	return func(t *http2.Transport) {
+		t.ReadIdleTimeout = d
+	}
+}
+
+// WithPingTimeout sets how long to wait for a PING response before the
+// connection is considered dead and closed.
+func WithPingTimeout(d time.Duration) HTTP2Option {
+	return func(t *http2.Transport) {
+		t.PingTimeout = d
+	}
+}
+
+// WithStrictMaxConcurrentStreams makes the server's advertised
+// SETTINGS_MAX_CONCURRENT_STREAMS a global cap enforced across all of the
+// client's connections to that server, rather than a per-connection cap that
+// the client works around by opening additional connections.
+func WithStrictMaxConcurrentStreams(strict bool) HTTP2Option {
+	return func(t *http2.Transport) {
+		t.StrictMaxConcurrentStreams = strict
+	}
+}
+
+// WithH2C enables h2c: HTTP/2 over a plain-text, non-TLS connection using
+// prior knowledge (no Upgrade handshake). It implies AllowHTTP and dials
+// plain TCP instead of TLS.
+func WithH2C() HTTP2Option {
+	return func(t *http2.Transport) {
+		t.AllowHTTP = true
+		t.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}
+	}
+}
+
+// WithHTTP2 enables HTTP/2 over the client's existing *http.Transport (or a
+// freshly created one) via ALPN negotiation, so requests transparently
+// upgrade to HTTP/2 when the server supports it and fall back to HTTP/1.1
+// otherwise. It composes with WithTLSConfig and WithTransport since it
+// upgrades whatever *http.Transport is already installed; apply it after
+// those options. opts are applied to the resulting *http2.Transport.
+func WithHTTP2(opts ...HTTP2Option) Option {
+	return func(hc *HTTPClient) {
+		base, ok := hc.client.Transport.(*http.Transport)
+		if !ok {
+			base = &http.Transport{}
+			hc.client.Transport = base
+		}
+		if base.TLSClientConfig == nil {
+			base.TLSClientConfig = &tls.Config{}
+		}
+		addNextProtoH2(base.TLSClientConfig)
+
+		h2Transport, err := http2.ConfigureTransports(base)
+		if err != nil {
+			hc.logger.Errorf("httpclient: failed to configure HTTP/2: %v", err)
+			return
+		}
+		for _, opt := range opts {
+			opt(h2Transport)
+		}
+	}
+}
+
+// WithForceHTTP2 replaces the client's transport with an *http2.Transport
+// directly, bypassing http.Transport and its HTTP/1.1 fallback entirely.
+// Combine with WithH2C to speak h2c prior-knowledge to a plain-text server.
+func WithForceHTTP2(opts ...HTTP2Option) Option {
+	return func(hc *HTTPClient) {
+		t := &http2.Transport{}
+		if transport, ok := hc.client.Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+			t.TLSClientConfig = transport.TLSClientConfig.Clone()
+			addNextProtoH2(t.TLSClientConfig)
+		}
+		for _, opt := range opts {
+			opt(t)
+		}
+		hc.client.Transport = t
+	}
+}
+
+// addNextProtoH2 ensures cfg advertises "h2" via ALPN without clobbering any
+// protocols already configured by the caller (e.g. via WithTLSConfig).
+func addNextProtoH2(cfg *tls.Config) {
+	for _, proto := range cfg.NextProtos {
+		if proto == "h2" {
+			return
+		}
+	}
+	cfg.NextProtos = append(cfg.NextProtos, "h2")
+}
@@ -0,0 +1,57 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestWithHTTP2_NegotiatesOverTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	client := NewHTTPClient(
+		WithTLSConfig(&tls.Config{InsecureSkipVerify: true}),
+		WithHTTP2(WithReadIdleTimeout(0)),
+	)
+
+	resp, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.ProtoMajor != 2 {
+		t.Errorf("expected HTTP/2 response, got proto %s", resp.Proto)
+	}
+}
+
+func TestWithForceHTTP2_H2CPriorKnowledge(t *testing.T) {
+	h2s := &http2.Server{}
+	server := httptest.NewUnstartedServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), h2s))
+	server.Start()
+	defer server.Close()
+
+	client := NewHTTPClient(WithForceHTTP2(WithH2C()))
+
+	resp, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.ProtoMajor != 2 {
+		t.Errorf("expected h2c response, got proto %s", resp.Proto)
+	}
+}
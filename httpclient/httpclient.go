@@ -2,6 +2,7 @@ package httpclient
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -11,6 +12,9 @@ import (
 	"math"
 	"net/http"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // WithRetry configures retry count and delay.
@@ -30,10 +34,11 @@ func WithExponentialBackoff(baseDelay time.Duration) Option {
 	}
 }
 
-// WithLogger sets a custom logger for the HTTP client.
+// WithLogger sets a custom logger for the HTTP client. It is adapted to the
+// Logger interface; see also WithSlogger.
 func WithLogger(logger *log.Logger) Option {
 	return func(hc *HTTPClient) {
-		hc.logger = logger
+		hc.logger = stdLogAdapter{l: logger}
 	}
 }
 
@@ -76,11 +81,21 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithRetryBudget caps the total time spent across all attempts of a single
+// Do/DoCtx call, regardless of the per-attempt retry delay. It is enforced by
+// deriving a child context with a deadline from the context passed to DoCtx
+// (or from req.Context() when called through Do).
+func WithRetryBudget(budget time.Duration) Option {
+	return func(hc *HTTPClient) {
+		hc.retryBudget = budget
+	}
+}
+
 // NewHTTPClient creates a new instance of HTTPClient with the provided options.
 func NewHTTPClient(options ...Option) *HTTPClient {
 	hc := &HTTPClient{
 		client:  &http.Client{},
-		logger:  log.Default(),
+		logger:  stdLogAdapter{l: log.Default()},
 		backoff: func(attempt int) time.Duration { return 0 }, // Default: no backoff
 	}
 	for _, opt := range options {
@@ -90,87 +105,246 @@ func NewHTTPClient(options ...Option) *HTTPClient {
 }
 
 // Do sends an HTTP request and returns an HTTP response, with retry and logging.
+// It routes through DoCtx using the context carried by req, so callers that
+// want cancellation or a deadline should build req with http.NewRequestWithContext.
 func (hc *HTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return hc.DoCtx(req.Context(), req)
+}
+
+// DoCtx sends an HTTP request bound to ctx, retrying with the configured
+// backoff while honoring cancellation and deadlines. The retry loop selects
+// between ctx.Done() and the backoff timer so a canceled or expired context
+// aborts the wait immediately with ctx.Err(). If WithRetryBudget was
+// configured, ctx is further bounded to that total duration across all
+// attempts.
+func (hc *HTTPClient) DoCtx(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if hc.retryBudget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hc.retryBudget)
+		defer cancel()
+	}
+
+	var span trace.Span
+	if hc.tracer != nil {
+		ctx, span = hc.tracer.Start(ctx, "httpclient.Do")
+		defer span.End()
+		span.SetAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", req.URL.String()),
+		)
+	}
+	req = req.WithContext(ctx)
+
+	if err := ensureRewindableBody(req); err != nil {
+		err = fmt.Errorf("httpclient: buffering request body for retries: %w", err)
+		if span != nil {
+			span.RecordError(err)
+		}
+		return nil, err
+	}
+
+	resp, err, attempts := hc.retryLoop(ctx, req)
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("http.retry_count", attempts-1))
+		if err != nil {
+			span.RecordError(err)
+		} else {
+			span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+		}
+	}
+	return resp, err
+}
+
+// retryLoop runs the attempt/backoff loop and reports how many attempts it
+// made, for DoCtx's tracing span.
+func (hc *HTTPClient) retryLoop(ctx context.Context, req *http.Request) (*http.Response, error, int) {
 	var lastErr error
+	var pendingDelay time.Duration
 	for i := 0; i <= hc.retryCount; i++ {
 		if i > 0 {
-			delay := hc.retryDelay
-			if hc.backoff != nil {
-				delay = hc.backoff(i)
+			if err := rewindBody(req); err != nil {
+				return nil, fmt.Errorf("httpclient: rewinding request body for retry: %w", err), i
+			}
+
+			hc.logger.Infof("Retrying request (%d/%d) after %v...", i, hc.retryCount, pendingDelay)
+
+			timer := time.NewTimer(pendingDelay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err(), i
+			case <-timer.C:
 			}
-			hc.logger.Printf("Retrying request (%d/%d) after %v...", i, hc.retryCount, delay)
-			time.Sleep(delay)
 		}
 
+		start := time.Now()
 		resp, err := hc.client.Do(req)
+		latency := time.Since(start)
+
+		if err == nil && hc.autoDecompress {
+			if decErr := decompressBody(resp); decErr != nil {
+				hc.recordEvent(req, i+1, resp, decErr, latency, 0, false)
+				resp.Body.Close()
+				return nil, decErr, i + 1
+			}
+		}
+
 		if err != nil {
-			hc.logger.Printf("Request failed: %v", err)
+			if ctx.Err() != nil {
+				hc.recordEvent(req, i+1, nil, err, latency, 0, false)
+				return nil, ctx.Err(), i + 1
+			}
+			hc.logger.Warnf("Request failed: %v", err)
 			lastErr = err
+			retry := true
+			if hc.retryPolicy != nil {
+				var policyErr error
+				retry, policyErr = hc.retryPolicy.ShouldRetry(req, nil, err, i+1)
+				if policyErr != nil {
+					hc.recordEvent(req, i+1, nil, err, latency, 0, false)
+					return nil, policyErr, i + 1
+				}
+			}
+			if !retry {
+				hc.recordEvent(req, i+1, nil, err, latency, 0, false)
+				return nil, lastErr, i + 1
+			}
+			pendingDelay = hc.retryDelayFor(i+1, nil)
+			hc.recordEvent(req, i+1, nil, err, latency, pendingDelay, false)
+			continue
+		}
+
+		if hc.retryPolicy != nil {
+			retry, policyErr := hc.retryPolicy.ShouldRetry(req, resp, nil, i+1)
+			if policyErr != nil {
+				resp.Body.Close()
+				hc.recordEvent(req, i+1, resp, nil, latency, 0, false)
+				return nil, policyErr, i + 1
+			}
+			if !retry {
+				hc.recordEvent(req, i+1, resp, nil, latency, 0, true)
+				return resp, nil, i + 1
+			}
+			hc.logger.Warnf("Retry policy requested retry after status: %d", resp.StatusCode)
+			lastErr = fmt.Errorf("non-2xx response received: %d", resp.StatusCode)
+			pendingDelay = hc.retryDelayFor(i+1, resp)
+			hc.recordEvent(req, i+1, resp, nil, latency, pendingDelay, false)
+			resp.Body.Close()
 			continue
 		}
 
 		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			return resp, nil
+			hc.recordEvent(req, i+1, resp, nil, latency, 0, true)
+			return resp, nil, i + 1
 		}
 
-		hc.logger.Printf("Received non-2xx response: %d", resp.StatusCode)
+		hc.logger.Warnf("Received non-2xx response: %d", resp.StatusCode)
 		lastErr = errors.New("non-2xx response received")
+		pendingDelay = hc.retryDelayFor(i+1, resp)
+		hc.recordEvent(req, i+1, resp, nil, latency, pendingDelay, false)
 		resp.Body.Close()
 	}
 
-	return nil, lastErr
+	return nil, lastErr, hc.retryCount + 1
+}
+
+// retryDelayFor computes the wait before attempt. A Retry-After header on the
+// previous response (resp) takes precedence; otherwise the pluggable
+// RetryPolicy's Backoff is used when set, and failing that the legacy
+// backoff function (WithExponentialBackoff, or the zero-delay default).
+// WithRetry's delay applies only when no backoff function is set.
+func (hc *HTTPClient) retryDelayFor(attempt int, resp *http.Response) time.Duration {
+	delay := hc.retryDelay
+	switch {
+	case hc.retryPolicy != nil:
+		delay = hc.retryPolicy.Backoff(attempt, resp)
+	case hc.backoff != nil:
+		delay = hc.backoff(attempt)
+	}
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			delay = retryAfter
+		}
+	}
+	return delay
 }
 
 // Get is a helper method for making GET requests.
 func (hc *HTTPClient) Get(url string, headers map[string]string) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	return hc.GetCtx(context.Background(), url, headers)
+}
+
+// GetCtx is the context-aware variant of Get.
+func (hc *HTTPClient) GetCtx(ctx context.Context, url string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
-	return hc.Do(req)
+	return hc.DoCtx(ctx, req)
 }
 
 // Post is a helper method for making POST requests.
 func (hc *HTTPClient) Post(url string, body io.Reader, headers map[string]string) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodPost, url, body)
+	return hc.PostCtx(context.Background(), url, body, headers)
+}
+
+// PostCtx is the context-aware variant of Post.
+func (hc *HTTPClient) PostCtx(ctx context.Context, url string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
 	if err != nil {
 		return nil, err
 	}
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
-	return hc.Do(req)
+	return hc.DoCtx(ctx, req)
 }
 
 // Put is a helper method for making PUT requests.
 func (hc *HTTPClient) Put(url string, body io.Reader, headers map[string]string) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodPut, url, body)
+	return hc.PutCtx(context.Background(), url, body, headers)
+}
+
+// PutCtx is the context-aware variant of Put.
+func (hc *HTTPClient) PutCtx(ctx context.Context, url string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, body)
 	if err != nil {
 		return nil, err
 	}
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
-	return hc.Do(req)
+	return hc.DoCtx(ctx, req)
 }
 
 // Delete is a helper method for making DELETE requests.
 func (hc *HTTPClient) Delete(url string, headers map[string]string) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	return hc.DeleteCtx(context.Background(), url, headers)
+}
+
+// DeleteCtx is the context-aware variant of Delete.
+func (hc *HTTPClient) DeleteCtx(ctx context.Context, url string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return nil, err
 	}
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
-	return hc.Do(req)
+	return hc.DoCtx(ctx, req)
 }
 
 // PostJSON is a helper method for making POST requests with JSON body.
 func (hc *HTTPClient) PostJSON(url string, jsonBody interface{}, headers map[string]string) (*http.Response, error) {
+	return hc.PostJSONCtx(context.Background(), url, jsonBody, headers)
+}
+
+// PostJSONCtx is the context-aware variant of PostJSON.
+func (hc *HTTPClient) PostJSONCtx(ctx context.Context, url string, jsonBody interface{}, headers map[string]string) (*http.Response, error) {
 	// Ensure headers map is initialized before adding Content-Type.
 	if headers == nil {
 		headers = make(map[string]string)
@@ -180,7 +354,7 @@ func (hc *HTTPClient) PostJSON(url string, jsonBody interface{}, headers map[str
 		return nil, err
 	}
 	headers["Content-Type"] = "application/json"
-	return hc.Post(url, bytes.NewReader(body), headers)
+	return hc.PostCtx(ctx, url, bytes.NewReader(body), headers)
 }
 
 // ReadResponseBody reads and returns the response body as a string.
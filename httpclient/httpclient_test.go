@@ -2,6 +2,7 @@ package httpclient
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"errors"
 	"io"
@@ -30,7 +31,7 @@ func TestNewHTTPClient(t *testing.T) {
 	if client.retryDelay != 1*time.Second {
 		t.Errorf("Expected retry delay to be 1 second, got %v", client.retryDelay)
 	}
-	if client.logger != logger {
+	if adapter, ok := client.logger.(stdLogAdapter); !ok || adapter.l != logger {
 		t.Errorf("Expected logger to be set correctly")
 	}
 }
@@ -318,6 +319,242 @@ func TestHTTPClient_DefaultHeaders(t *testing.T) {
 	}
 }
 
+func TestDoCtx_CancelAbortsRetryWait(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(WithRetry(5, 0), WithExponentialBackoff(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.Do(req)
+		errCh <- err
+	}()
+
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Do did not return after context cancellation")
+	}
+}
+
+func TestDoCtx_DeadlineExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(
+		WithRetry(5, 0),
+		WithExponentialBackoff(50*time.Millisecond),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, err := client.Do(req)
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestWithRetryBudget_CapsTotalRetryTime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(
+		WithRetry(10, 0),
+		WithExponentialBackoff(20*time.Millisecond),
+		WithRetryBudget(30*time.Millisecond),
+	)
+
+	start := time.Now()
+	_, err := client.GetCtx(context.Background(), server.URL, nil)
+	elapsed := time.Since(start)
+
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed > 200*time.Millisecond {
+		t.Errorf("expected retry budget to cap total wait, took %v", elapsed)
+	}
+}
+
+func TestWithRetryPolicy_DefaultPolicySkipsClientErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(
+		WithRetry(3, 0),
+		WithRetryPolicy(NewDefaultRetryPolicy(WithFullJitter(time.Millisecond, 5*time.Millisecond))),
+	)
+
+	resp, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no transport error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if attempts != 1 {
+		t.Errorf("expected 4xx responses not to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestWithRetryPolicy_DefaultPolicyRetries5xx(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(
+		WithRetry(5, 0),
+		WithRetryPolicy(NewDefaultRetryPolicy(WithFullJitter(time.Millisecond, 5*time.Millisecond))),
+	)
+
+	resp, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestIdempotentOnlyPolicy_SkipsNonIdempotentMethod(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy := &IdempotentOnlyPolicy{Wrapped: NewDefaultRetryPolicy(WithFullJitter(time.Millisecond, 5*time.Millisecond))}
+	client := NewHTTPClient(WithRetry(3, 0), WithRetryPolicy(policy))
+
+	resp, err := client.Post(server.URL, strings.NewReader("payload"), nil)
+	if err != nil {
+		t.Fatalf("expected no transport error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if attempts != 1 {
+		t.Errorf("expected POST not to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestIdempotentOnlyPolicy_SkipsNonIdempotentMethodOnTransportError(t *testing.T) {
+	attempts := 0
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return nil, errors.New("connection reset by peer")
+	})
+
+	policy := &IdempotentOnlyPolicy{Wrapped: NewDefaultRetryPolicy(WithFullJitter(time.Millisecond, 5*time.Millisecond))}
+	client := NewHTTPClient(WithRetry(3, 0), WithRetryPolicy(policy), WithTransport(transport))
+
+	_, err := client.Post("http://example.invalid", strings.NewReader("payload"), nil)
+	if err == nil {
+		t.Fatal("expected a transport error")
+	}
+
+	if attempts != 1 {
+		t.Errorf("expected POST not to be retried after a transport error, got %d attempts", attempts)
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper for tests that need
+// to simulate transport-level failures without a real server.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRetryAfter_OverridesComputedBackoff(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(
+		WithRetry(3, time.Hour),
+		WithRetryPolicy(NewDefaultRetryPolicy(func(int, *http.Response) time.Duration { return time.Hour })),
+	)
+
+	start := time.Now()
+	resp, err := client.Get(server.URL, nil)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+	if elapsed > time.Second {
+		t.Errorf("expected Retry-After: 0 to override the hour-long backoff, took %v", elapsed)
+	}
+}
+
+func TestDo_RetryRewindsRequestBody(t *testing.T) {
+	attempts := 0
+	var gotBodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		gotBodies = append(gotBodies, string(body))
+		if attempts < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(WithRetry(2, 0))
+	resp, err := client.Post(server.URL, strings.NewReader("retry me"), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	for i, body := range gotBodies {
+		if body != "retry me" {
+			t.Errorf("attempt %d: expected body to be rewound to %q, got %q", i+1, "retry me", body)
+		}
+	}
+}
+
 type errorReader struct{}
 
 func (e *errorReader) Read(p []byte) (n int, err error) {
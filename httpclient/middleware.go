@@ -0,0 +1,240 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrCircuitOpen is returned when a circuit-breaker-wrapped transport
+// rejects a request because the breaker for that host is open; the request
+// never touches the network.
+var ErrCircuitOpen = errors.New("httpclient: circuit breaker open")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// BreakerConfig configures a per-host circuit breaker installed by
+// WithCircuitBreaker.
+type BreakerConfig struct {
+	// FailureThreshold trips the breaker after this many consecutive
+	// failures. Zero disables the consecutive-failure trigger.
+	FailureThreshold int
+	// FailureRatio trips the breaker once the fraction of failed attempts
+	// within Window reaches this value. Zero disables the ratio trigger.
+	FailureRatio float64
+	// Window is the rolling window FailureRatio is evaluated over.
+	Window time.Duration
+	// OpenTimeout is how long the breaker stays open before letting a
+	// single half-open probe request through.
+	OpenTimeout time.Duration
+	// IsFailure classifies a completed attempt as a failure. Defaults to
+	// transport errors and 5xx responses.
+	IsFailure func(resp *http.Response, err error) bool
+}
+
+func (cfg BreakerConfig) isFailure(resp *http.Response, err error) bool {
+	if cfg.IsFailure != nil {
+		return cfg.IsFailure(resp, err)
+	}
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= 500
+}
+
+type breakerEvent struct {
+	at      time.Time
+	failure bool
+}
+
+// hostBreaker tracks one host's circuit breaker state.
+type hostBreaker struct {
+	mu               sync.Mutex
+	state            breakerState
+	consecutiveFails int
+	openedAt         time.Time
+	events           []breakerEvent
+	halfOpenProbe    bool
+}
+
+// allow reports whether a request may proceed. In the half-open state only
+// one concurrent caller is let through as the probe; every other caller is
+// rejected until record resolves that probe back to closed or open, which
+// prevents a thundering herd from all hitting the network the instant
+// OpenTimeout elapses.
+func (hb *hostBreaker) allow(cfg BreakerConfig) bool {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+
+	switch hb.state {
+	case breakerClosed:
+		return true
+	case breakerHalfOpen:
+		if hb.halfOpenProbe {
+			return false
+		}
+		hb.halfOpenProbe = true
+		return true
+	default: // breakerOpen
+		if time.Since(hb.openedAt) < cfg.OpenTimeout {
+			return false
+		}
+		hb.state = breakerHalfOpen
+		hb.halfOpenProbe = true
+		return true
+	}
+}
+
+func (hb *hostBreaker) record(cfg BreakerConfig, resp *http.Response, err error) {
+	failed := cfg.isFailure(resp, err)
+	now := time.Now()
+
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+
+	if hb.state == breakerHalfOpen {
+		if failed {
+			hb.trip(now)
+		} else {
+			hb.reset()
+		}
+		return
+	}
+
+	if failed {
+		hb.consecutiveFails++
+	} else {
+		hb.consecutiveFails = 0
+	}
+
+	if cfg.Window > 0 {
+		hb.events = append(hb.events, breakerEvent{at: now, failure: failed})
+		hb.pruneLocked(now, cfg.Window)
+	}
+
+	if cfg.FailureThreshold > 0 && hb.consecutiveFails >= cfg.FailureThreshold {
+		hb.trip(now)
+		return
+	}
+
+	if cfg.Window > 0 && cfg.FailureRatio > 0 && len(hb.events) > 0 {
+		failures := 0
+		for _, e := range hb.events {
+			if e.failure {
+				failures++
+			}
+		}
+		if float64(failures)/float64(len(hb.events)) >= cfg.FailureRatio {
+			hb.trip(now)
+		}
+	}
+}
+
+func (hb *hostBreaker) trip(now time.Time) {
+	hb.state = breakerOpen
+	hb.openedAt = now
+	hb.consecutiveFails = 0
+	hb.events = nil
+	hb.halfOpenProbe = false
+}
+
+func (hb *hostBreaker) reset() {
+	hb.state = breakerClosed
+	hb.consecutiveFails = 0
+	hb.events = nil
+	hb.halfOpenProbe = false
+}
+
+func (hb *hostBreaker) pruneLocked(now time.Time, window time.Duration) {
+	cutoff := now.Add(-window)
+	i := 0
+	for i < len(hb.events) && hb.events[i].at.Before(cutoff) {
+		i++
+	}
+	hb.events = hb.events[i:]
+}
+
+// circuitBreakerTransport decorates a RoundTripper with a three-state
+// (closed/open/half-open) breaker keyed by request host, chained the same
+// way headerTransport wraps a base RoundTripper.
+type circuitBreakerTransport struct {
+	base http.RoundTripper
+	cfg  BreakerConfig
+
+	mu       sync.Mutex
+	breakers map[string]*hostBreaker
+}
+
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	hb := t.breakerFor(req.URL.Host)
+
+	if !hb.allow(t.cfg) {
+		return nil, ErrCircuitOpen
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	hb.record(t.cfg, resp, err)
+	return resp, err
+}
+
+func (t *circuitBreakerTransport) breakerFor(host string) *hostBreaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	hb, ok := t.breakers[host]
+	if !ok {
+		hb = &hostBreaker{}
+		t.breakers[host] = hb
+	}
+	return hb
+}
+
+// WithCircuitBreaker wraps the client's transport with a circuit breaker
+// keyed by host, per cfg.
+func WithCircuitBreaker(cfg BreakerConfig) Option {
+	return func(hc *HTTPClient) {
+		base := hc.client.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		hc.client.Transport = &circuitBreakerTransport{
+			base:     base,
+			cfg:      cfg,
+			breakers: make(map[string]*hostBreaker),
+		}
+	}
+}
+
+// rateLimitedTransport decorates a RoundTripper, gating every request on rl
+// and aborting the wait if the request's context is canceled first.
+type rateLimitedTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// WithRateLimiter gates every request through rl, blocking (respecting the
+// request's context) until a token is available.
+func WithRateLimiter(rl *rate.Limiter) Option {
+	return func(hc *HTTPClient) {
+		base := hc.client.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		hc.client.Transport = &rateLimitedTransport{base: base, limiter: rl}
+	}
+}
@@ -0,0 +1,173 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestCircuitBreaker_TripsAfterConsecutiveFailures(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(WithCircuitBreaker(BreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      time.Hour,
+	}))
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Get(server.URL, nil)
+		if errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("attempt %d: breaker tripped too early", i)
+		}
+	}
+
+	_, err := client.Get(server.URL, nil)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen after tripping, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected the network to be skipped once open, got %d attempts", attempts)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenRecoversOnSuccess(t *testing.T) {
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(WithCircuitBreaker(BreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Millisecond,
+	}))
+
+	if _, err := client.Get(server.URL, nil); errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("breaker should not be open before the first failure: %v", err)
+	}
+
+	if _, err := client.Get(server.URL, nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected breaker to be open, got %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	fail = false
+
+	resp, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected half-open probe to succeed, got %v", err)
+	}
+	resp.Body.Close()
+
+	resp, err = client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected breaker to have closed again, got %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestCircuitBreaker_HalfOpenAllowsOnlyOneConcurrentProbe(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			if cur := atomic.LoadInt32(&maxInFlight); n > cur {
+				if atomic.CompareAndSwapInt32(&maxInFlight, cur, n) {
+					break
+				}
+				continue
+			}
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(WithCircuitBreaker(BreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Millisecond,
+	}))
+
+	if _, err := client.Get(server.URL, nil); err == nil {
+		t.Fatal("expected the first request's 500 response to surface as an error")
+	}
+	if _, err := client.Get(server.URL, nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected breaker to be open after the first failure, got %v", err)
+	}
+
+	failing.Store(false)
+	time.Sleep(15 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	var rejected int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL, nil)
+			if err != nil {
+				if errors.Is(err, ErrCircuitOpen) {
+					atomic.AddInt32(&rejected, 1)
+					return
+				}
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 1 {
+		t.Errorf("expected at most 1 concurrent half-open probe to reach the network, got %d", maxInFlight)
+	}
+	if rejected != 9 {
+		t.Errorf("expected 9 of 10 concurrent callers to be rejected while the probe was in flight, got %d", rejected)
+	}
+}
+
+func TestWithRateLimiter_GatesRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(WithRateLimiter(rate.NewLimiter(rate.Every(20*time.Millisecond), 1)))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL, nil)
+		if err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+		resp.Body.Close()
+	}
+	elapsed := time.Since(start)
+
+	if elapsed < 30*time.Millisecond {
+		t.Errorf("expected rate limiter to space out requests, took %v", elapsed)
+	}
+}
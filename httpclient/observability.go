@@ -0,0 +1,223 @@
+package httpclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Logger is the structured logging interface used internally by HTTPClient.
+// WithLogger and WithSlogger adapt *log.Logger and *slog.Logger to it.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// stdLogAdapter adapts a *log.Logger to Logger. log.Logger has no notion of
+// level, so every method writes through the same underlying logger.
+type stdLogAdapter struct {
+	l *log.Logger
+}
+
+func (a stdLogAdapter) Debugf(format string, args ...interface{}) { a.l.Printf(format, args...) }
+func (a stdLogAdapter) Infof(format string, args ...interface{})  { a.l.Printf(format, args...) }
+func (a stdLogAdapter) Warnf(format string, args ...interface{})  { a.l.Printf(format, args...) }
+func (a stdLogAdapter) Errorf(format string, args ...interface{}) { a.l.Printf(format, args...) }
+
+// slogAdapter adapts a *slog.Logger to Logger.
+type slogAdapter struct {
+	l *slog.Logger
+}
+
+func (a slogAdapter) Debugf(format string, args ...interface{}) { a.l.Debug(fmt.Sprintf(format, args...)) }
+func (a slogAdapter) Infof(format string, args ...interface{})  { a.l.Info(fmt.Sprintf(format, args...)) }
+func (a slogAdapter) Warnf(format string, args ...interface{})  { a.l.Warn(fmt.Sprintf(format, args...)) }
+func (a slogAdapter) Errorf(format string, args ...interface{}) { a.l.Error(fmt.Sprintf(format, args...)) }
+
+// WithSlogger sets logger, adapted to Logger, as the client's logger.
+func WithSlogger(logger *slog.Logger) Option {
+	return func(hc *HTTPClient) {
+		hc.logger = slogAdapter{l: logger}
+	}
+}
+
+// LogMode controls how much of a request/response WithRequestLogging
+// captures.
+type LogMode int
+
+const (
+	// LogHeaders records method, URL, status, and headers only.
+	LogHeaders LogMode = iota
+	// LogBodies additionally records request and response bodies.
+	LogBodies
+	// LogRedacted behaves like LogBodies but masks sensitive header values
+	// (Authorization, Cookie, and any names added via WithRedactedHeaders).
+	LogRedacted
+)
+
+// RequestLog is a captured snapshot of an outgoing request.
+type RequestLog struct {
+	Method  string
+	URL     string
+	Headers http.Header
+	Body    string
+}
+
+// ResponseLog is a captured snapshot of a received response.
+type ResponseLog struct {
+	StatusCode int
+	Headers    http.Header
+	Body       string
+}
+
+// Event is one observation recorded during a Do/DoCtx call when WithCapture
+// is configured: the outcome of a single attempt, plus the request/response
+// snapshots WithRequestLogging asked for.
+type Event struct {
+	Attempt     int
+	Method      string
+	URL         string
+	StatusCode  int
+	Latency     time.Duration
+	NextBackoff time.Duration
+	Err         error
+	Request     *RequestLog
+	Response    *ResponseLog
+}
+
+var defaultRedactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+}
+
+const redactedValue = "[REDACTED]"
+
+// WithRequestLogging captures a RequestLog/ResponseLog pair for every
+// attempt at the level of detail mode specifies. Captured events are only
+// retained if WithCapture is also configured.
+func WithRequestLogging(mode LogMode) Option {
+	return func(hc *HTTPClient) {
+		hc.logMode = mode
+	}
+}
+
+// WithRedactedHeaders adds header names to mask (in addition to the
+// defaults, Authorization and Cookie) when LogMode is LogRedacted.
+func WithRedactedHeaders(names ...string) Option {
+	return func(hc *HTTPClient) {
+		if hc.redactedHeaders == nil {
+			hc.redactedHeaders = make(map[string]bool, len(defaultRedactedHeaders)+len(names))
+			for name := range defaultRedactedHeaders {
+				hc.redactedHeaders[name] = true
+			}
+		}
+		for _, name := range names {
+			hc.redactedHeaders[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// WithCapture appends an Event to *events for every attempt made by Do/DoCtx,
+// so tests can inspect retries, latencies, and (with WithRequestLogging)
+// request/response bodies without a real logging backend.
+func WithCapture(events *[]Event) Option {
+	return func(hc *HTTPClient) {
+		hc.capture = events
+	}
+}
+
+// recordEvent appends an Event for one attempt to *hc.capture, if configured.
+// finalResponse reports whether resp is the one DoCtx is about to hand back
+// to the original caller: if so, its body is teed into the log and replaced
+// with an equivalent unread reader rather than being consumed outright, so
+// capturing it never steals bytes from the caller's response.
+func (hc *HTTPClient) recordEvent(req *http.Request, attempt int, resp *http.Response, err error, latency, nextBackoff time.Duration, finalResponse bool) {
+	if hc.capture == nil {
+		return
+	}
+	ev := Event{
+		Attempt:     attempt,
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		Latency:     latency,
+		NextBackoff: nextBackoff,
+		Err:         err,
+	}
+	ev.Request = hc.captureRequestLog(req)
+	if resp != nil {
+		ev.StatusCode = resp.StatusCode
+		ev.Response = hc.captureResponseLog(resp, finalResponse)
+	}
+	*hc.capture = append(*hc.capture, ev)
+}
+
+// captureRequestLog snapshots req at the detail level hc.logMode specifies.
+func (hc *HTTPClient) captureRequestLog(req *http.Request) *RequestLog {
+	rl := &RequestLog{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: hc.captureHeaders(req.Header),
+	}
+	if hc.logMode >= LogBodies && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			rl.Body = string(data)
+		}
+	}
+	return rl
+}
+
+// captureResponseLog snapshots resp at the detail level hc.logMode
+// specifies. When finalResponse is false, resp is about to be closed by the
+// retry loop without being returned further, so its body is simply read and
+// left consumed. When finalResponse is true, resp is the response DoCtx is
+// about to hand back to the caller, so the body is read, then resp.Body is
+// replaced with a fresh reader over the same bytes so the caller can still
+// read it in full.
+func (hc *HTTPClient) captureResponseLog(resp *http.Response, finalResponse bool) *ResponseLog {
+	rl := &ResponseLog{
+		StatusCode: resp.StatusCode,
+		Headers:    hc.captureHeaders(resp.Header),
+	}
+	if hc.logMode < LogBodies {
+		return rl
+	}
+	if !finalResponse {
+		data, _ := io.ReadAll(resp.Body)
+		rl.Body = string(data)
+		return rl
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err == nil {
+		rl.Body = string(data)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return rl
+}
+
+// captureHeaders clones headers, masking any name present in
+// hc.redactedHeaders (or the defaults) when hc.logMode is LogRedacted.
+func (hc *HTTPClient) captureHeaders(headers http.Header) http.Header {
+	out := headers.Clone()
+	if hc.logMode != LogRedacted {
+		return out
+	}
+	redacted := hc.redactedHeaders
+	if redacted == nil {
+		redacted = defaultRedactedHeaders
+	}
+	for name := range out {
+		if redacted[http.CanonicalHeaderKey(name)] {
+			out.Set(name, redactedValue)
+		}
+	}
+	return out
+}
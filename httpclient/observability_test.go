@@ -0,0 +1,120 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithCapture_RecordsAttemptsAndRetries(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var events []Event
+	client := NewHTTPClient(WithRetry(2, 0), WithCapture(&events))
+
+	resp, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 recorded events, got %d", len(events))
+	}
+	if events[0].StatusCode != http.StatusInternalServerError || events[0].Attempt != 1 {
+		t.Errorf("unexpected first event: %+v", events[0])
+	}
+	if events[1].StatusCode != http.StatusOK || events[1].Attempt != 2 {
+		t.Errorf("unexpected second event: %+v", events[1])
+	}
+}
+
+func TestWithCapture_RecordsFinalResponseBodyAndLeavesItReadable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("hello from the server"))
+	}))
+	defer server.Close()
+
+	var events []Event
+	client := NewHTTPClient(WithRequestLogging(LogBodies), WithCapture(&events))
+
+	resp, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if len(events) != 1 || events[0].Response == nil {
+		t.Fatalf("expected a captured response, got %+v", events)
+	}
+	if events[0].Response.Body != "hello from the server" {
+		t.Errorf("expected the captured response body to be recorded, got %q", events[0].Response.Body)
+	}
+
+	body, err := ReadResponseBody(resp)
+	if err != nil {
+		t.Fatalf("expected the caller to still be able to read the body, got %v", err)
+	}
+	if body != "hello from the server" {
+		t.Errorf("expected caller to see the full body, got %q", body)
+	}
+}
+
+func TestWithRequestLogging_RedactsAuthorizationHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var events []Event
+	client := NewHTTPClient(WithRequestLogging(LogRedacted), WithCapture(&events))
+
+	resp, err := client.Get(server.URL, map[string]string{"Authorization": "secret-token"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if len(events) != 1 || events[0].Request == nil {
+		t.Fatalf("expected a captured request, got %+v", events)
+	}
+	if got := events[0].Request.Headers.Get("Authorization"); got != redactedValue {
+		t.Errorf("expected Authorization to be redacted, got %q", got)
+	}
+}
+
+func TestWithTracer_RecordsSpans(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	client := NewHTTPClient(WithTracer(tp))
+
+	resp, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans (1 call + 1 attempt), got %d", len(spans))
+	}
+}
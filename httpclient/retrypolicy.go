@@ -0,0 +1,186 @@
+package httpclient
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides whether a completed attempt should be retried and how
+// long to wait before the next one. Setting one via WithRetryPolicy replaces
+// the client's hard-coded "retry on non-2xx" behavior.
+type RetryPolicy interface {
+	// ShouldRetry reports whether attempt (1-indexed) should be retried. req
+	// is the request that was sent; resp is nil when err is non-nil, i.e.
+	// the request never got a response.
+	ShouldRetry(req *http.Request, resp *http.Response, err error, attempt int) (bool, error)
+	// Backoff returns how long to wait before the attempt-th retry. resp is
+	// the response from the previous attempt, or nil after a transport error.
+	Backoff(attempt int, resp *http.Response) time.Duration
+}
+
+// BackoffFunc computes the delay before a retry attempt, optionally taking
+// the previous response into account.
+type BackoffFunc func(attempt int, resp *http.Response) time.Duration
+
+// WithRetryPolicy installs a pluggable RetryPolicy, overriding WithRetry's
+// "retry on non-2xx" default and WithExponentialBackoff's delay calculation.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(hc *HTTPClient) {
+		hc.retryPolicy = policy
+	}
+}
+
+// DefaultRetryPolicy retries network errors and 5xx/429 responses, leaving
+// other 4xx responses alone since retrying them rarely succeeds.
+type DefaultRetryPolicy struct {
+	// BackoffFn computes the delay for each retry. Defaults to
+	// WithFullJitter(100ms, 10s) when nil.
+	BackoffFn BackoffFunc
+}
+
+// NewDefaultRetryPolicy builds a DefaultRetryPolicy using backoff, or full
+// jitter between 100ms and 10s when backoff is nil.
+func NewDefaultRetryPolicy(backoff BackoffFunc) *DefaultRetryPolicy {
+	if backoff == nil {
+		backoff = WithFullJitter(100*time.Millisecond, 10*time.Second)
+	}
+	return &DefaultRetryPolicy{BackoffFn: backoff}
+}
+
+// ShouldRetry implements RetryPolicy.
+func (p *DefaultRetryPolicy) ShouldRetry(req *http.Request, resp *http.Response, err error, attempt int) (bool, error) {
+	if err != nil {
+		return true, nil
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, nil
+}
+
+// Backoff implements RetryPolicy.
+func (p *DefaultRetryPolicy) Backoff(attempt int, resp *http.Response) time.Duration {
+	return p.BackoffFn(attempt, resp)
+}
+
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// IdempotentOnlyPolicy wraps another RetryPolicy and only lets it retry
+// requests whose method is safe to repeat (GET, HEAD, PUT, DELETE, OPTIONS).
+// This applies equally to transport errors (resp == nil): a POST that times
+// out or has its connection reset mid-flight may already have been
+// processed server-side, so it is just as unsafe to replay as one that got
+// a response back.
+type IdempotentOnlyPolicy struct {
+	Wrapped RetryPolicy
+}
+
+// ShouldRetry implements RetryPolicy.
+func (p *IdempotentOnlyPolicy) ShouldRetry(req *http.Request, resp *http.Response, err error, attempt int) (bool, error) {
+	if req != nil && !idempotentMethods[req.Method] {
+		return false, nil
+	}
+	return p.Wrapped.ShouldRetry(req, resp, err, attempt)
+}
+
+// Backoff implements RetryPolicy.
+func (p *IdempotentOnlyPolicy) Backoff(attempt int, resp *http.Response) time.Duration {
+	return p.Wrapped.Backoff(attempt, resp)
+}
+
+// WithFullJitter returns a BackoffFunc implementing the "full jitter"
+// strategy (https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/):
+// random(base, min(cap, base*2^attempt)). It avoids the thundering-herd
+// effect of plain exponential backoff when many clients retry in lockstep.
+func WithFullJitter(base, cap time.Duration) BackoffFunc {
+	return func(attempt int, resp *http.Response) time.Duration {
+		upper := expCapped(base, cap, attempt)
+		if upper <= base {
+			return base
+		}
+		return base + time.Duration(rand.Int63n(int64(upper-base)))
+	}
+}
+
+// WithDecorrelatedJitter returns a BackoffFunc implementing the
+// "decorrelated jitter" strategy. The canonical version randomizes between
+// base and 3x the previous sleep; since Backoff is stateless across calls,
+// this approximates it as random(base, min(cap, base*2^attempt)).
+func WithDecorrelatedJitter(base, cap time.Duration) BackoffFunc {
+	return func(attempt int, resp *http.Response) time.Duration {
+		upper := expCapped(base, cap, attempt)
+		return base + time.Duration(rand.Int63n(int64(upper-base)+1))
+	}
+}
+
+func expCapped(base, cap time.Duration, attempt int) time.Duration {
+	scaled := float64(base) * math.Pow(2, float64(attempt))
+	if scaled <= 0 || scaled > float64(cap) {
+		return cap
+	}
+	return time.Duration(scaled)
+}
+
+// parseRetryAfter parses a Retry-After header value, accepting both the
+// delay-seconds and HTTP-date forms from RFC 7231. ok is false when value is
+// empty or unparseable.
+func parseRetryAfter(value string) (delay time.Duration, ok bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	when, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if delay = time.Until(when); delay < 0 {
+		delay = 0
+	}
+	return delay, true
+}
+
+// ensureRewindableBody buffers req.Body into req.GetBody on its first call so
+// the request can be safely resent on retry. It is a no-op when the body is
+// already rewindable (GetBody set, e.g. by NewRequestWithContext for a
+// []byte/bytes.Buffer/strings.Reader body) or absent.
+func ensureRewindableBody(req *http.Request) error {
+	if req.Body == nil || req.GetBody != nil {
+		return nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body.Close()
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return rewindBody(req)
+}
+
+// rewindBody resets req.Body from req.GetBody ahead of a retry attempt.
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
@@ -0,0 +1,185 @@
+package httpclient
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// ReadResponseBodyLimit reads resp.Body into a string like ReadResponseBody,
+// but returns an error instead of buffering an unbounded amount of data when
+// the body exceeds maxBytes.
+func ReadResponseBodyLimit(resp *http.Response, maxBytes int64) (string, error) {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(body)) > maxBytes {
+		return "", fmt.Errorf("httpclient: response body exceeds limit of %d bytes", maxBytes)
+	}
+	return string(body), nil
+}
+
+// StreamJSON decodes resp.Body as a stream of JSON values - e.g.
+// line-delimited JSON or a top-level JSON array - invoking fn once per value
+// available in decoder. Iteration stops when decoder runs out of values or
+// fn returns an error; io.EOF from fn is treated as a clean stop rather than
+// a failure. The response body is always closed before StreamJSON returns.
+func StreamJSON(resp *http.Response, fn func(decoder *json.Decoder) error) error {
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for decoder.More() {
+		if err := fn(decoder); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// WithAutoDecompress transparently decodes gzip, deflate, and br
+// (Content-Encoding) response bodies so callers always see plain bytes. It
+// is applied to every attempt, so a retried request that receives a
+// compressed response stays decompressed just like the final one.
+func WithAutoDecompress() Option {
+	return func(hc *HTTPClient) {
+		hc.autoDecompress = true
+	}
+}
+
+// decompressBody rewraps resp.Body to transparently undo Content-Encoding,
+// if any was set, leaving resp.Body untouched when there's nothing to do.
+func decompressBody(resp *http.Response) error {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+
+	var decoded io.Reader
+	var closer io.Closer
+	switch encoding {
+	case "":
+		return nil
+	case "gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return fmt.Errorf("httpclient: decoding gzip response: %w", err)
+		}
+		decoded, closer = gz, gz
+	case "deflate":
+		fl := flate.NewReader(resp.Body)
+		decoded, closer = fl, fl
+	case "br":
+		decoded = brotli.NewReader(resp.Body)
+	default:
+		return nil
+	}
+
+	resp.Body = &decompressedBody{Reader: decoded, decoder: closer, orig: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	return nil
+}
+
+// decompressedBody closes both the decompressor (if it holds resources, as
+// gzip.Reader and flate's Reader do) and the original response body.
+type decompressedBody struct {
+	io.Reader
+	decoder io.Closer
+	orig    io.ReadCloser
+}
+
+func (d *decompressedBody) Close() error {
+	if d.decoder != nil {
+		if err := d.decoder.Close(); err != nil {
+			d.orig.Close()
+			return err
+		}
+	}
+	return d.orig.Close()
+}
+
+// DownloadTo downloads url into w, adding headers to the request.
+func (hc *HTTPClient) DownloadTo(url string, w io.Writer, headers map[string]string) error {
+	return hc.DownloadToCtx(context.Background(), url, w, headers)
+}
+
+// DownloadToCtx is the context-aware variant of DownloadTo. If w implements
+// io.Seeker and is positioned past the start, DownloadToCtx checks whether
+// the server advertises Accept-Ranges: bytes via a HEAD request, and if so
+// resumes the download with a "Range: bytes=<offset>-" request instead of
+// restarting from scratch. The HEAD probe is only ever a hint: per RFC 7233
+// a server may still ignore the Range request and answer 200 with the full
+// body, so DownloadToCtx trusts the actual response status, not the probe,
+// when deciding whether the response is a resumed partial body. Whenever
+// it isn't, w is seeked (and truncated, if it supports that) back to the
+// start before the full body is written, so the result is never a mix of
+// old and new bytes.
+func (hc *HTTPClient) DownloadToCtx(ctx context.Context, url string, w io.Writer, headers map[string]string) error {
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+
+	seeker, seekable := w.(io.Seeker)
+	if seekable {
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		if offset > 0 && hc.supportsRangeResume(ctx, url) {
+			headers["Range"] = fmt.Sprintf("bytes=%d-", offset)
+		}
+	}
+
+	resp, err := hc.GetCtx(ctx, url, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if seekable && resp.StatusCode != http.StatusPartialContent {
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if truncater, ok := w.(interface{ Truncate(int64) error }); ok {
+			if err := truncater.Truncate(0); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// supportsRangeResume issues a HEAD request to check whether the server
+// advertises byte-range support before DownloadToCtx commits to a Range
+// request.
+func (hc *HTTPClient) supportsRangeResume(ctx context.Context, url string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := hc.DoCtx(ctx, req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.Header.Get("Accept-Ranges") == "bytes"
+}
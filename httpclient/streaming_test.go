@@ -0,0 +1,213 @@
+package httpclient
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadResponseBodyLimit_ErrorsWhenTooLarge(t *testing.T) {
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader("0123456789"))}
+
+	_, err := ReadResponseBodyLimit(resp, 5)
+	if err == nil {
+		t.Fatal("expected an error for a body over the limit")
+	}
+}
+
+func TestReadResponseBodyLimit_AllowsUnderLimit(t *testing.T) {
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader("hello"))}
+
+	body, err := ReadResponseBodyLimit(resp, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if body != "hello" {
+		t.Errorf("expected %q, got %q", "hello", body)
+	}
+}
+
+func TestWithAutoDecompress_DecodesGzipBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusOK)
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte("decompressed payload"))
+		gz.Close()
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(WithAutoDecompress())
+	resp, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	body, err := ReadResponseBody(resp)
+	if err != nil {
+		t.Fatalf("expected no error reading body, got %v", err)
+	}
+	if body != "decompressed payload" {
+		t.Errorf("expected decompressed body, got %q", body)
+	}
+}
+
+func TestStreamJSON_DecodesEachValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"n":1}{"n":2}{"n":3}`))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient()
+	resp, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var got []int
+	err = StreamJSON(resp, func(decoder *json.Decoder) error {
+		var v struct {
+			N int `json:"n"`
+		}
+		if err := decoder.Decode(&v); err != nil {
+			return err
+		}
+		got = append(got, v.N)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("expected [1 2 3], got %v", got)
+	}
+}
+
+func TestDownloadTo_ResumesWithRangeHeader(t *testing.T) {
+	const full = "0123456789"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		rangeHeader := r.Header.Get("Range")
+		if rangeHeader == "" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(full))
+			return
+		}
+		w.Header().Set("Content-Range", "bytes 5-9/10")
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte(full[5:]))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient()
+	buf := &seekableBuffer{data: []byte(full[:5])}
+	buf.offset = 5
+
+	if err := client.DownloadTo(server.URL, buf, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if buf.String() != full {
+		t.Errorf("expected resumed download to equal %q, got %q", full, buf.String())
+	}
+}
+
+func TestDownloadTo_IgnoresHeadAdvertisedRangeWhenGetReturns200(t *testing.T) {
+	const full = "0123456789"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			// Advertises range support, but the GET below ignores the Range
+			// header anyway - a proxy/CDN-like server that lies.
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(full))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient()
+	buf := &seekableBuffer{data: []byte("01234")}
+	buf.offset = 5
+
+	if err := client.DownloadTo(server.URL, buf, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if buf.String() != full {
+		t.Errorf("expected the ignored-Range 200 response to overwrite stale bytes and equal %q, got %q", full, buf.String())
+	}
+}
+
+func TestDownloadTo_FallsBackToFullDownloadReseeksWriter(t *testing.T) {
+	const full = "0123456789"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			// No Accept-Ranges: bytes, so range resume is unsupported.
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(full))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient()
+	buf := &seekableBuffer{data: []byte("01234")}
+	buf.offset = 5
+
+	if err := client.DownloadTo(server.URL, buf, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if buf.String() != full {
+		t.Errorf("expected full redownload to overwrite stale bytes and equal %q, got %q", full, buf.String())
+	}
+}
+
+// seekableBuffer is a minimal io.Writer+io.Seeker+Truncate so DownloadTo's
+// range-resume and non-resumable-fallback paths can be exercised without a
+// real file.
+type seekableBuffer struct {
+	data   []byte
+	offset int64
+}
+
+func (b *seekableBuffer) Write(p []byte) (int, error) {
+	if int64(len(b.data)) < b.offset {
+		b.data = append(b.data, make([]byte, b.offset-int64(len(b.data)))...)
+	}
+	b.data = append(b.data[:b.offset], p...)
+	b.offset += int64(len(p))
+	return len(p), nil
+}
+
+func (b *seekableBuffer) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case 0:
+		b.offset = offset
+	case 1:
+		b.offset += offset
+	case 2:
+		b.offset = int64(len(b.data)) + offset
+	}
+	return b.offset, nil
+}
+
+func (b *seekableBuffer) Truncate(size int64) error {
+	if int64(len(b.data)) > size {
+		b.data = b.data[:size]
+	}
+	return nil
+}
+
+func (b *seekableBuffer) String() string {
+	return string(b.data)
+}
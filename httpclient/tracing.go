@@ -0,0 +1,56 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTracer enables OpenTelemetry-style tracing, similar to otelhttp: DoCtx
+// starts one span covering the whole call, and the installed transport
+// starts a child span per attempt (including retries) recording
+// http.method, http.url, and http.status_code.
+func WithTracer(tp trace.TracerProvider) Option {
+	return func(hc *HTTPClient) {
+		hc.tracer = tp.Tracer("httpclient")
+		base := hc.client.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		hc.client.Transport = &tracingTransport{base: base, tracer: hc.tracer}
+	}
+}
+
+// tracingTransport decorates a RoundTripper with one child span per
+// RoundTrip call, chained the same way headerTransport wraps a base
+// RoundTripper.
+type tracingTransport struct {
+	base   http.RoundTripper
+	tracer trace.Tracer
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := t.tracer.Start(req.Context(), fmt.Sprintf("HTTP %s", req.Method))
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("http.method", req.Method),
+		attribute.String("http.url", req.URL.String()),
+	)
+
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
@@ -1,18 +1,26 @@
 package httpclient
 
 import (
-	"log"
 	"net/http"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // HTTPClient is a wrapper around the standard http.Client with additional features.
 type HTTPClient struct {
-	client     *http.Client
-	retryCount int
-	retryDelay time.Duration
-	logger     *log.Logger
-	backoff    func(attempt int) time.Duration
+	client          *http.Client
+	retryCount      int
+	retryDelay      time.Duration
+	retryBudget     time.Duration
+	retryPolicy     RetryPolicy
+	logger          Logger
+	backoff         func(attempt int) time.Duration
+	logMode         LogMode
+	redactedHeaders map[string]bool
+	capture         *[]Event
+	tracer          trace.Tracer
+	autoDecompress  bool
 }
 
 // Option is a functional option for configuring the HTTPClient.